@@ -0,0 +1,124 @@
+package oauth
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+const (
+	defaultCacheCapacity = 1000
+	defaultMaxCacheTTL   = 5 * time.Minute
+	negativeCacheTTL     = 10 * time.Second
+)
+
+// TokenCache lets callers plug in their own storage (Redis, Memcached, ...)
+// for resolved access tokens instead of relying on the built-in in-memory LRU.
+// A Get that returns ok=true and a nil token means the id is negatively
+// cached, i.e. it is known to not exist.
+type TokenCache interface {
+	Get(id string) (tok *accessToken, ok bool)
+	Set(id string, tok *accessToken, ttl time.Duration)
+}
+
+var (
+	tokenCache  TokenCache = newLRUCache(defaultCacheCapacity)
+	maxCacheTTL            = defaultMaxCacheTTL
+)
+
+// SetTokenCache overrides the default in-memory LRU cache used by getAccessToken.
+func SetTokenCache(cache TokenCache) {
+	if cache == nil {
+		return
+	}
+	tokenCache = cache
+}
+
+// SetMaxCacheTTL caps the TTL derived from a token's expires field.
+func SetMaxCacheTTL(ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+	maxCacheTTL = ttl
+}
+
+// ttlFromExpires turns a token's absolute expires timestamp into a TTL,
+// clamped to maxCacheTTL. A zero/negative result means the token is already
+// expired and must not be cached.
+func ttlFromExpires(expires int64) time.Duration {
+	if expires <= 0 {
+		return 0
+	}
+	ttl := time.Until(time.Unix(expires, 0))
+	if ttl > maxCacheTTL {
+		return maxCacheTTL
+	}
+	return ttl
+}
+
+type cacheEntry struct {
+	id        string
+	tok       *accessToken
+	expiresAt time.Time
+}
+
+// lruCache is a small, mutex-guarded, fixed-capacity LRU and is the default
+// TokenCache implementation.
+type lruCache struct {
+	mu       sync.Mutex
+	capacity int
+	ll       *list.List
+	items    map[string]*list.Element
+}
+
+func newLRUCache(capacity int) *lruCache {
+	return &lruCache{
+		capacity: capacity,
+		ll:       list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+func (c *lruCache) Get(id string) (*accessToken, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[id]
+	if !ok {
+		return nil, false
+	}
+
+	entry := el.Value.(*cacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.ll.Remove(el)
+		delete(c.items, id)
+		return nil, false
+	}
+
+	c.ll.MoveToFront(el)
+	return entry.tok, true
+}
+
+func (c *lruCache) Set(id string, tok *accessToken, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[id]; ok {
+		entry := el.Value.(*cacheEntry)
+		entry.tok = tok
+		entry.expiresAt = time.Now().Add(ttl)
+		c.ll.MoveToFront(el)
+		return
+	}
+
+	el := c.ll.PushFront(&cacheEntry{id: id, tok: tok, expiresAt: time.Now().Add(ttl)})
+	c.items[id] = el
+
+	if c.ll.Len() > c.capacity {
+		oldest := c.ll.Back()
+		if oldest != nil {
+			c.ll.Remove(oldest)
+			delete(c.items, oldest.Value.(*cacheEntry).id)
+		}
+	}
+}
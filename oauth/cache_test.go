@@ -0,0 +1,98 @@
+package oauth
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func resetTokenCache() {
+	SetTokenCache(newLRUCache(defaultCacheCapacity))
+}
+
+func TestGetAccessTokenCacheMiss(t *testing.T) {
+	resetTokenCache()
+	defer httpmock.Reset()
+
+	accessTokenId := "cache-miss"
+	fixture := fmt.Sprintf(`{"access_token":"%s","user_id":1,"client_id":2,"expires":%d}`, accessTokenId, time.Now().Add(time.Hour).Unix())
+	fakeUrl := fmt.Sprintf("http://localhost:8080/oauth/access_token/%s", accessTokenId)
+	httpmock.RegisterResponder("GET", fakeUrl, httpmock.NewStringResponder(200, fixture))
+	httpmock.ActivateNonDefault(oauthRestClient.GetClient())
+
+	response, err := getAccessToken(accessTokenId)
+
+	assert.Nil(t, err)
+	assert.NotNil(t, response)
+	assert.EqualValues(t, 1, httpmock.GetTotalCallCount())
+}
+
+func TestGetAccessTokenCacheHit(t *testing.T) {
+	resetTokenCache()
+	defer httpmock.Reset()
+
+	accessTokenId := "cache-hit"
+	fixture := fmt.Sprintf(`{"access_token":"%s","user_id":1,"client_id":2,"expires":%d}`, accessTokenId, time.Now().Add(time.Hour).Unix())
+	fakeUrl := fmt.Sprintf("http://localhost:8080/oauth/access_token/%s", accessTokenId)
+	httpmock.RegisterResponder("GET", fakeUrl, httpmock.NewStringResponder(200, fixture))
+	httpmock.ActivateNonDefault(oauthRestClient.GetClient())
+
+	first, err := getAccessToken(accessTokenId)
+	assert.Nil(t, err)
+	assert.NotNil(t, first)
+
+	httpmock.RegisterResponder("GET", fakeUrl, httpmock.NewStringResponder(500, `{"message":"should not be called","status":500,"error":"internal_server_error","causes":null}`))
+
+	second, err := getAccessToken(accessTokenId)
+	assert.Nil(t, err)
+	assert.NotNil(t, second)
+	assert.EqualValues(t, first, second)
+}
+
+func TestGetAccessTokenCacheExpiry(t *testing.T) {
+	resetTokenCache()
+	defer httpmock.Reset()
+
+	accessTokenId := "cache-expiry"
+	fixture := fmt.Sprintf(`{"access_token":"%s","user_id":1,"client_id":2,"expires":%d}`, accessTokenId, time.Now().Add(50*time.Millisecond).Unix())
+	fakeUrl := fmt.Sprintf("http://localhost:8080/oauth/access_token/%s", accessTokenId)
+	httpmock.RegisterResponder("GET", fakeUrl, httpmock.NewStringResponder(200, fixture))
+	httpmock.ActivateNonDefault(oauthRestClient.GetClient())
+
+	_, err := getAccessToken(accessTokenId)
+	assert.Nil(t, err)
+
+	time.Sleep(1100 * time.Millisecond)
+
+	_, hit := tokenCache.Get(accessTokenId)
+	assert.False(t, hit)
+
+	_, err = getAccessToken(accessTokenId)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, httpmock.GetTotalCallCount())
+}
+
+func TestGetAccessTokenNegativeCache(t *testing.T) {
+	resetTokenCache()
+	defer httpmock.Reset()
+
+	accessTokenId := "cache-not-found"
+	fixture := `{"message":"token not exist","status":404,"error":"not found","causes":null}`
+	fakeUrl := fmt.Sprintf("http://localhost:8080/oauth/access_token/%s", accessTokenId)
+	httpmock.RegisterResponder("GET", fakeUrl, httpmock.NewStringResponder(404, fixture))
+	httpmock.ActivateNonDefault(oauthRestClient.GetClient())
+
+	_, err := getAccessToken(accessTokenId)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusNotFound, err.Status())
+
+	httpmock.RegisterResponder("GET", fakeUrl, httpmock.NewStringResponder(500, `{"message":"should not be called","status":500,"error":"internal_server_error","causes":null}`))
+
+	_, err = getAccessToken(accessTokenId)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusNotFound, err.Status())
+}
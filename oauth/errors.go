@@ -0,0 +1,42 @@
+package oauth
+
+import (
+	"net/http"
+
+	"github.com/go-resty/resty"
+	"github.com/tv2169145/store_utils-go/rest_errors"
+)
+
+// DetailedResponse carries the raw upstream response an AuthenticationError
+// was built from (status, headers, body, parsed causes), similar to the
+// response wrapper IBM's go-sdk-core attaches to its errors.
+type DetailedResponse struct {
+	StatusCode int
+	Headers    http.Header
+	RawResult  []byte
+	Causes     []interface{}
+}
+
+// AuthenticationError wraps a rest_errors.RestErr with the upstream OAuth
+// server response that produced it, so callers that need more than a
+// message/status (logging, rate-limit headers, ...) can still get at it. It
+// embeds rest_errors.RestErr so it keeps satisfying that interface.
+type AuthenticationError struct {
+	rest_errors.RestErr
+	Response *DetailedResponse
+}
+
+// newAuthenticationError wraps restErr with the *resty.Response the OAuth
+// server returned, if any.
+func newAuthenticationError(restErr rest_errors.RestErr, response *resty.Response) *AuthenticationError {
+	detailed := &DetailedResponse{}
+	if response != nil {
+		detailed.StatusCode = response.StatusCode()
+		detailed.Headers = response.Header()
+		detailed.RawResult = response.Body()
+	}
+	if restErr != nil {
+		detailed.Causes = restErr.Causes()
+	}
+	return &AuthenticationError{RestErr: restErr, Response: detailed}
+}
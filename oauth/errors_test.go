@@ -0,0 +1,77 @@
+package oauth
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetAccessTokenNotFoundReturnsAuthenticationError(t *testing.T) {
+	resetTokenCache()
+	defer httpmock.Reset()
+
+	accessTokenId := "auth-error-404"
+	fixture := `{"message":"token not exist","status":404,"error":"not found","causes":null}`
+	fakeUrl := fmt.Sprintf("http://localhost:8080/oauth/access_token/%s", accessTokenId)
+	httpmock.RegisterResponder("GET", fakeUrl, httpmock.NewStringResponder(404, fixture))
+	httpmock.ActivateNonDefault(oauthRestClient.GetClient())
+
+	_, err := getAccessToken(accessTokenId)
+
+	assert.NotNil(t, err)
+	authErr, ok := err.(*AuthenticationError)
+	assert.True(t, ok)
+	assert.EqualValues(t, http.StatusNotFound, authErr.Status())
+	assert.NotNil(t, authErr.Response)
+	assert.EqualValues(t, http.StatusNotFound, authErr.Response.StatusCode)
+	assert.EqualValues(t, []byte(fixture), authErr.Response.RawResult)
+}
+
+func TestGetAccessTokenNegativeCacheHitReturnsAuthenticationError(t *testing.T) {
+	resetTokenCache()
+	defer httpmock.Reset()
+
+	accessTokenId := "auth-error-404-cached"
+	fixture := `{"message":"token not exist","status":404,"error":"not found","causes":null}`
+	fakeUrl := fmt.Sprintf("http://localhost:8080/oauth/access_token/%s", accessTokenId)
+	httpmock.RegisterResponder("GET", fakeUrl, httpmock.NewStringResponder(404, fixture))
+	httpmock.ActivateNonDefault(oauthRestClient.GetClient())
+
+	_, err := getAccessToken(accessTokenId)
+	assert.NotNil(t, err)
+	_, ok := err.(*AuthenticationError)
+	assert.True(t, ok)
+
+	// Second lookup is served from the negative cache, not the fixture above,
+	// but must still come back as an *AuthenticationError so callers that
+	// type-assert it don't see a different error type depending on whether
+	// the cache happened to be warm.
+	_, err = getAccessToken(accessTokenId)
+	assert.NotNil(t, err)
+	authErr, ok := err.(*AuthenticationError)
+	assert.True(t, ok)
+	assert.EqualValues(t, http.StatusNotFound, authErr.Status())
+	assert.NotNil(t, authErr.Response)
+}
+
+func TestGetAccessTokenServerErrorReturnsAuthenticationError(t *testing.T) {
+	resetTokenCache()
+	defer httpmock.Reset()
+
+	accessTokenId := "auth-error-500"
+	fixture := `{"message":"server error","status":500,"error":"database error","causes":null}`
+	fakeUrl := fmt.Sprintf("http://localhost:8080/oauth/access_token/%s", accessTokenId)
+	httpmock.RegisterResponder("GET", fakeUrl, httpmock.NewStringResponder(500, fixture))
+	httpmock.ActivateNonDefault(oauthRestClient.GetClient())
+
+	_, err := getAccessToken(accessTokenId)
+
+	assert.NotNil(t, err)
+	authErr, ok := err.(*AuthenticationError)
+	assert.True(t, ok)
+	assert.EqualValues(t, http.StatusInternalServerError, authErr.Status())
+	assert.NotNil(t, authErr.Response.Headers)
+}
@@ -0,0 +1,93 @@
+package oauth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testFlow(t *testing.T, tokenURL string) *Flow {
+	flow, err := NewFlow(Config{
+		ClientId:     "client-id",
+		ClientSecret: "client-secret",
+		RedirectURI:  "http://localhost:8080/callback",
+		Provider:     GoogleProvider(),
+		StateSecret:  []byte("super-secret"),
+	})
+	assert.Nil(t, err)
+	if tokenURL != "" {
+		flow.config.Provider = fakeProvider{tokenURL: tokenURL}
+	}
+	return flow
+}
+
+type fakeProvider struct {
+	tokenURL string
+}
+
+func (fakeProvider) AuthURL() string         { return "https://example.com/auth" }
+func (f fakeProvider) TokenURL() string      { return f.tokenURL }
+func (fakeProvider) DefaultScopes() []string { return []string{"default"} }
+
+func TestNewFlowMissingProvider(t *testing.T) {
+	_, err := NewFlow(Config{StateSecret: []byte("secret")})
+	assert.NotNil(t, err)
+}
+
+func TestNewFlowMissingStateSecret(t *testing.T) {
+	_, err := NewFlow(Config{Provider: GoogleProvider()})
+	assert.NotNil(t, err)
+}
+
+func TestGetBeginAuthURL(t *testing.T) {
+	flow := testFlow(t, "")
+
+	authURL, err := flow.GetBeginAuthURL("xyz", map[string]string{"prompt": "consent"})
+
+	assert.Nil(t, err)
+	parsed, err := url.Parse(authURL)
+	assert.Nil(t, err)
+	query := parsed.Query()
+	assert.EqualValues(t, "client-id", query.Get("client_id"))
+	assert.EqualValues(t, "http://localhost:8080/callback", query.Get("redirect_uri"))
+	assert.EqualValues(t, "openid email profile", query.Get("scope"))
+	assert.EqualValues(t, "consent", query.Get("prompt"))
+	assert.NotEmpty(t, query.Get("state"))
+}
+
+func TestCompleteAuthSuccess(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"at-123","token_type":"Bearer","expires_in":3600}`))
+	}))
+	defer server.Close()
+
+	flow := testFlow(t, server.URL)
+	authURL, err := flow.GetBeginAuthURL("xyz", nil)
+	assert.Nil(t, err)
+	state := mustStateFromURL(t, authURL)
+
+	tok, err := flow.CompleteAuth("some-code", state)
+
+	assert.Nil(t, err)
+	assert.NotNil(t, tok)
+	assert.EqualValues(t, "at-123", tok.AccessToken)
+	assert.True(t, tok.Expiry().After(tok.obtainedAt))
+}
+
+func TestCompleteAuthTamperedState(t *testing.T) {
+	flow := testFlow(t, "")
+
+	_, err := flow.CompleteAuth("some-code", "tampered-state")
+
+	assert.NotNil(t, err)
+}
+
+func mustStateFromURL(t *testing.T, rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	assert.Nil(t, err)
+	return parsed.Query().Get("state")
+}
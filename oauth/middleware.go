@@ -0,0 +1,92 @@
+package oauth
+
+import (
+	"context"
+	"net/http"
+)
+
+// FailMode controls what Middleware does when a request fails authentication.
+type FailMode int
+
+const (
+	// FailClosed rejects every request that fails authentication. This is the default.
+	FailClosed FailMode = iota
+	// FailOpenIfPublic lets the request through to next if it is marked public
+	// (see IsPublic), even though authentication failed.
+	FailOpenIfPublic
+)
+
+// Option configures Middleware.
+type Option func(*middlewareOptions)
+
+type middlewareOptions struct {
+	failMode FailMode
+}
+
+// WithFailMode sets the FailMode Middleware uses when authentication fails.
+func WithFailMode(mode FailMode) Option {
+	return func(o *middlewareOptions) {
+		o.failMode = mode
+	}
+}
+
+// CallerInfo is the caller identity Middleware resolves and stores in the
+// request context for CallerFromContext to retrieve.
+type CallerInfo struct {
+	UserID   int64
+	ClientID int64
+	Scopes   []string
+	Token    *accessToken
+}
+
+type callerContextKey struct{}
+
+// Middleware runs AuthenticateRequest's resolution logic and, on success,
+// stores the resolved caller in the request context instead of in headers,
+// so downstream handlers can use CallerFromContext rather than trusting
+// header values a client could have spoofed. It does not set the
+// X-Caller-Id, X-Client-Id or X-Caller-Scopes headers.
+func Middleware(opts ...Option) func(http.Handler) http.Handler {
+	options := middlewareOptions{failMode: FailClosed}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			at, err := authenticate(r)
+			if err != nil {
+				if options.failMode == FailOpenIfPublic && IsPublic(r) {
+					next.ServeHTTP(w, r)
+					return
+				}
+				http.Error(w, err.Message(), err.Status())
+				return
+			}
+
+			if at == nil {
+				if options.failMode == FailOpenIfPublic && IsPublic(r) {
+					next.ServeHTTP(w, r)
+					return
+				}
+				http.Error(w, "missing or invalid access token", http.StatusUnauthorized)
+				return
+			}
+
+			caller := CallerInfo{
+				UserID:   at.UserId,
+				ClientID: at.ClientId,
+				Scopes:   at.Scopes,
+				Token:    at,
+			}
+			ctx := context.WithValue(r.Context(), callerContextKey{}, caller)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// CallerFromContext retrieves the CallerInfo Middleware stored in ctx.
+func CallerFromContext(ctx context.Context) (CallerInfo, bool) {
+	caller, ok := ctx.Value(callerContextKey{}).(CallerInfo)
+	return caller, ok
+}
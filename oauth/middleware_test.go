@@ -0,0 +1,104 @@
+package oauth
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddlewareFailClosedRejectsInvalidToken(t *testing.T) {
+	resetTokenCache()
+	resetRateLimiter()
+	defer httpmock.Reset()
+
+	accessTokenId := "mw-not-found"
+	fixture := `{"message":"token not exist","status":404,"error":"not found","causes":null}`
+	fakeUrl := fmt.Sprintf("http://localhost:8080/oauth/access_token/%s", accessTokenId)
+	httpmock.RegisterResponder("GET", fakeUrl, httpmock.NewStringResponder(404, fixture))
+	httpmock.ActivateNonDefault(oauthRestClient.GetClient())
+
+	handlerCalled := false
+	handler := Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handlerCalled = true
+	}))
+
+	request := httptest.NewRequest(http.MethodGet, fmt.Sprintf("http://localhost:8080?access_token=%s", accessTokenId), nil)
+	recorder := httptest.NewRecorder()
+
+	handler.ServeHTTP(recorder, request)
+
+	assert.False(t, handlerCalled)
+	assert.EqualValues(t, http.StatusUnauthorized, recorder.Code)
+}
+
+func TestMiddlewareFailOpenIfPublicAllowsPublicRequest(t *testing.T) {
+	resetTokenCache()
+	resetRateLimiter()
+
+	handlerCalled := false
+	handler := Middleware(WithFailMode(FailOpenIfPublic))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handlerCalled = true
+	}))
+
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:8080", nil)
+	request.Header.Set(headerXPublic, "true")
+	recorder := httptest.NewRecorder()
+
+	handler.ServeHTTP(recorder, request)
+
+	assert.True(t, handlerCalled)
+	assert.EqualValues(t, http.StatusOK, recorder.Code)
+}
+
+func TestMiddlewareStoresCallerInContext(t *testing.T) {
+	resetTokenCache()
+	resetRateLimiter()
+	defer httpmock.Reset()
+
+	accessTokenId := "mw-success"
+	fixture := fmt.Sprintf(`{"access_token":"%s","user_id":1,"client_id":2,"expires":%d,"scopes":["read","write"]}`, accessTokenId, time.Now().Add(time.Hour).Unix())
+	fakeUrl := fmt.Sprintf("http://localhost:8080/oauth/access_token/%s", accessTokenId)
+	httpmock.RegisterResponder("GET", fakeUrl, httpmock.NewStringResponder(200, fixture))
+	httpmock.ActivateNonDefault(oauthRestClient.GetClient())
+
+	var caller CallerInfo
+	var ok bool
+	handler := Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		caller, ok = CallerFromContext(r.Context())
+	}))
+
+	request := httptest.NewRequest(http.MethodGet, fmt.Sprintf("http://localhost:8080?access_token=%s", accessTokenId), nil)
+	recorder := httptest.NewRecorder()
+
+	handler.ServeHTTP(recorder, request)
+
+	assert.True(t, ok)
+	assert.EqualValues(t, 1, caller.UserID)
+	assert.EqualValues(t, 2, caller.ClientID)
+	assert.EqualValues(t, []string{"read", "write"}, caller.Scopes)
+}
+
+func TestMiddlewareCleansInboundHeaders(t *testing.T) {
+	resetTokenCache()
+	resetRateLimiter()
+
+	handler := Middleware(WithFailMode(FailOpenIfPublic))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.EqualValues(t, "", r.Header.Get(headerXClientId))
+		assert.EqualValues(t, "", r.Header.Get(headerXCallerId))
+	}))
+
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:8080", nil)
+	request.Header.Set(headerXPublic, "true")
+	request.Header.Set(headerXClientId, "999")
+	request.Header.Set(headerXCallerId, "999")
+	recorder := httptest.NewRecorder()
+
+	handler.ServeHTTP(recorder, request)
+
+	assert.EqualValues(t, http.StatusOK, recorder.Code)
+}
@@ -0,0 +1,176 @@
+package oauth
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty"
+	"github.com/tv2169145/store_utils-go/rest_errors"
+)
+
+const (
+	headerXPublic       = "X-Public"
+	headerXClientId     = "X-Client-Id"
+	headerXCallerId     = "X-Caller-Id"
+	headerXCallerScopes = "X-Caller-Scopes"
+
+	paramAccessToken = "access_token"
+)
+
+var (
+	oauthRestClient = resty.New()
+)
+
+type accessToken struct {
+	Id       string   `json:"id"`
+	UserId   int64    `json:"user_id"`
+	ClientId int64    `json:"client_id"`
+	Expires  int64    `json:"expires"`
+	Scopes   []string `json:"scopes,omitempty"`
+	Roles    []string `json:"roles,omitempty"`
+}
+
+func IsPublic(request *http.Request) bool {
+	if request == nil {
+		return true
+	}
+	return request.Header.Get(headerXPublic) == "true"
+}
+
+func GetCallerId(request *http.Request) int64 {
+	if request == nil {
+		return 0
+	}
+	callerId, err := strconv.ParseInt(request.Header.Get(headerXCallerId), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return callerId
+}
+
+func GetClientId(request *http.Request) int64 {
+	if request == nil {
+		return 0
+	}
+	clientId, err := strconv.ParseInt(request.Header.Get(headerXClientId), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return clientId
+}
+
+func AuthenticateRequest(request *http.Request) rest_errors.RestErr {
+	at, err := authenticate(request)
+	if err != nil {
+		return err
+	}
+	if at == nil {
+		return nil
+	}
+
+	request.Header.Add(headerXCallerId, fmt.Sprintf("%v", at.UserId))
+	request.Header.Add(headerXClientId, fmt.Sprintf("%v", at.ClientId))
+	if len(at.Scopes) > 0 {
+		request.Header.Add(headerXCallerScopes, strings.Join(at.Scopes, ","))
+	}
+	return nil
+}
+
+// authenticate is the shared core behind AuthenticateRequest and Middleware:
+// it cleans the inbound request and resolves its access_token, but leaves
+// deciding what to do with the result (write headers vs. stash in context)
+// to the caller.
+func authenticate(request *http.Request) (*accessToken, rest_errors.RestErr) {
+	if request == nil {
+		return nil, nil
+	}
+	cleanRequest(request)
+
+	// http://api.store.com/resource?access_token=abc123
+	accessTokenId := strings.TrimSpace(request.URL.Query().Get(paramAccessToken))
+	if accessTokenId == "" {
+		return nil, nil
+	}
+
+	at, err := getAccessToken(accessTokenId)
+	if err != nil {
+		if err.Status() == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return at, nil
+}
+
+// cleanRequest strips any caller/client identity headers a client may have
+// set on the way in, so they can only be set by this package after verification.
+func cleanRequest(request *http.Request) {
+	if request == nil {
+		return
+	}
+	request.Header.Del(headerXClientId)
+	request.Header.Del(headerXCallerId)
+	request.Header.Del(headerXCallerScopes)
+}
+
+// getAccessToken resolves accessTokenId against the token cache first and
+// only falls back to the OAuth server on a cache miss.
+func getAccessToken(accessTokenId string) (*accessToken, rest_errors.RestErr) {
+	if len(accessTokenId) == 0 {
+		return nil, rest_errors.NewInternalServerError("invalid access token id", rest_errors.NewError("empty access token id"))
+	}
+
+	if tok, hit := tokenCache.Get(accessTokenId); hit {
+		if tok == nil {
+			return nil, newAuthenticationError(rest_errors.NewNotFoundError("token not exist"), nil)
+		}
+		return tok, nil
+	}
+
+	if rateLimiter.exceeded() {
+		fraction, resetAt := RateLimitStatus()
+		restErr := rest_errors.NewRestError(
+			fmt.Sprintf("oauth backend rate limit threshold reached (%.0f%%), retry after %s", fraction*100, resetAt.Format(time.RFC3339)),
+			http.StatusServiceUnavailable,
+			"service unavailable",
+			nil,
+		)
+		return nil, newAuthenticationError(restErr, nil)
+	}
+
+	oauthRestClient.SetHostURL("http://localhost:8080")
+	oauthRestClient.SetTimeout(1 * time.Minute)
+	response, err := oauthRestClient.R().Get(fmt.Sprintf("/oauth/access_token/%s", accessTokenId))
+	rateLimiter.track(response)
+	if err != nil {
+		restErr := rest_errors.NewInternalServerError("invalid restclient response when trying to get access token", err)
+		return nil, newAuthenticationError(restErr, response)
+	}
+
+	if response.StatusCode() > 299 {
+		restErr, unmarshalErr := rest_errors.NewRestErrorFromBytes(response.Body())
+		if unmarshalErr != nil {
+			restErr = rest_errors.NewInternalServerError("invalid response body when unmarshal response to restErr", unmarshalErr)
+			return nil, newAuthenticationError(restErr, response)
+		}
+		if restErr.Status() == http.StatusNotFound {
+			tokenCache.Set(accessTokenId, nil, negativeCacheTTL)
+		}
+		return nil, newAuthenticationError(restErr, response)
+	}
+
+	var token accessToken
+	if err := json.Unmarshal(response.Body(), &token); err != nil {
+		return nil, rest_errors.NewInternalServerError("invalid response body when unmarshal response to token", err)
+	}
+
+	if ttl := ttlFromExpires(token.Expires); ttl > 0 {
+		tokenCache.Set(accessTokenId, &token, ttl)
+	}
+
+	return &token, nil
+}
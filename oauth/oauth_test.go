@@ -139,6 +139,7 @@ func TestGetAccessTokenUrlError(t *testing.T) {
 }
 
 func TestGetAccessTokenWithTokenNotFoundError(t *testing.T) {
+	resetTokenCache()
 	defer httpmock.Reset()
 	accessTokenId := "jimmy1234"
 	fixture := `{"message":"token not exist","status":404,"error":"not found","causes":null}`
@@ -154,6 +155,7 @@ func TestGetAccessTokenWithTokenNotFoundError(t *testing.T) {
 }
 
 func TestGetAccessTokenWithTokenNotFoundAndInvalidJsonResponse(t *testing.T) {
+	resetTokenCache()
 	defer httpmock.Reset()
 	accessTokenId := "jimmy1234"
 	fixture := `{"message":"token not exist","status":"404","error":"not found","causes":null}`
@@ -197,6 +199,7 @@ func TestAuthenticateRequestWithEmptyAccessTokenQueryString(t *testing.T) {
 }
 
 func TestAuthenticateRequestWithGetAccessTokenNotFoundError(t *testing.T) {
+	resetTokenCache()
 	accessTokenId := "jimmy1234"
 	request := httptest.NewRequest(http.MethodGet, fmt.Sprintf("http://localhost:8080?access_token=%s", accessTokenId), strings.NewReader(""))
 
@@ -213,6 +216,7 @@ func TestAuthenticateRequestWithGetAccessTokenNotFoundError(t *testing.T) {
 }
 
 func TestAuthenticateRequestWithGetAccessTokenServerError(t *testing.T) {
+	resetTokenCache()
 	accessTokenId := "jimmy1234"
 	request := httptest.NewRequest(http.MethodGet, fmt.Sprintf("http://localhost:8080?access_token=%s", accessTokenId), strings.NewReader(""))
 
@@ -0,0 +1,37 @@
+package oauth
+
+// googleProvider, githubProvider and spotifyProvider are the built-in
+// Provider implementations. Callers needing another identity provider can
+// implement the Provider interface themselves.
+type googleProvider struct{}
+
+func (googleProvider) AuthURL() string  { return "https://accounts.google.com/o/oauth2/v2/auth" }
+func (googleProvider) TokenURL() string { return "https://oauth2.googleapis.com/token" }
+func (googleProvider) DefaultScopes() []string {
+	return []string{"openid", "email", "profile"}
+}
+
+type githubProvider struct{}
+
+func (githubProvider) AuthURL() string  { return "https://github.com/login/oauth/authorize" }
+func (githubProvider) TokenURL() string { return "https://github.com/login/oauth/access_token" }
+func (githubProvider) DefaultScopes() []string {
+	return []string{"read:user"}
+}
+
+type spotifyProvider struct{}
+
+func (spotifyProvider) AuthURL() string  { return "https://accounts.spotify.com/authorize" }
+func (spotifyProvider) TokenURL() string { return "https://accounts.spotify.com/api/token" }
+func (spotifyProvider) DefaultScopes() []string {
+	return []string{"user-read-email"}
+}
+
+// GoogleProvider returns the Provider for Google's OAuth2 endpoints.
+func GoogleProvider() Provider { return googleProvider{} }
+
+// GitHubProvider returns the Provider for GitHub's OAuth2 endpoints.
+func GitHubProvider() Provider { return githubProvider{} }
+
+// SpotifyProvider returns the Provider for Spotify's OAuth2 endpoints.
+func SpotifyProvider() Provider { return spotifyProvider{} }
@@ -0,0 +1,156 @@
+package oauth
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty"
+)
+
+const (
+	headerXRatelimitLimit = "X-Ratelimit-Limit"
+	headerXRatelimitUsage = "X-Ratelimit-Usage"
+
+	defaultRateLimitThreshold = 0.9
+	rateLimitResetWindow      = time.Minute
+)
+
+// RateLimiter tracks how close the OAuth backend is to its rate limit, based
+// on the X-Ratelimit-Limit/X-Ratelimit-Usage response headers ("short,long"
+// comma-separated windows), so getAccessToken can back off before the
+// backend starts rejecting everything.
+type RateLimiter struct {
+	mu        sync.Mutex
+	fraction  float64
+	resetAt   time.Time
+	threshold float64
+}
+
+var rateLimiter = &RateLimiter{threshold: defaultRateLimitThreshold}
+
+// SetRateLimitThreshold overrides the usage fraction (0-1) at which
+// getAccessToken starts short-circuiting lookups instead of calling the
+// OAuth backend.
+func SetRateLimitThreshold(threshold float64) {
+	rateLimiter.mu.Lock()
+	defer rateLimiter.mu.Unlock()
+	rateLimiter.threshold = threshold
+}
+
+// RateLimitStatus reports the last observed usage fraction and when that
+// window is expected to reset.
+func RateLimitStatus() (fraction float64, resetAt time.Time) {
+	rateLimiter.mu.Lock()
+	defer rateLimiter.mu.Unlock()
+	return rateLimiter.fraction, rateLimiter.resetAt
+}
+
+// track parses the rate-limit headers off an OAuth server response and
+// updates the limiter's state. Invalid/nonsense headers reset the limiter
+// to zero rather than leaving stale state in place or crashing.
+func (r *RateLimiter) track(response *resty.Response) {
+	if response == nil {
+		return
+	}
+
+	fraction, ok := parseRateLimitFraction(
+		response.Header().Get(headerXRatelimitLimit),
+		response.Header().Get(headerXRatelimitUsage),
+	)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !ok {
+		r.fraction = 0
+		r.resetAt = time.Time{}
+		return
+	}
+	r.fraction = fraction
+	resetAt := parseResetAt(response.Header().Get("Date"))
+	if resetAt.IsZero() && fraction >= r.threshold {
+		// No (or garbage) Date header to derive a reset time from, but we're
+		// breaching threshold right now: fall back to a window measured from
+		// this breach so exceeded() doesn't block forever. Since getAccessToken
+		// stops calling track() once it starts short-circuiting, this is the
+		// only chance to give the limiter a deadline to self-heal by.
+		resetAt = time.Now().Add(rateLimitResetWindow)
+	}
+	r.resetAt = resetAt
+}
+
+// exceeded reports whether the last observed usage fraction is at or above
+// threshold and the reset window has not passed yet.
+func (r *RateLimiter) exceeded() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.fraction < r.threshold {
+		return false
+	}
+	if r.resetAt.IsZero() {
+		return false
+	}
+	return time.Now().Before(r.resetAt)
+}
+
+// parseRateLimitFraction parses "short,long" X-Ratelimit-Limit/Usage headers
+// and returns the higher of the two usage fractions.
+func parseRateLimitFraction(limitHeader, usageHeader string) (float64, bool) {
+	limits, ok := parseCommaPair(limitHeader)
+	if !ok {
+		return 0, false
+	}
+	usages, ok := parseCommaPair(usageHeader)
+	if !ok {
+		return 0, false
+	}
+
+	shortFraction, ok := safeFraction(usages[0], limits[0])
+	if !ok {
+		return 0, false
+	}
+	longFraction, ok := safeFraction(usages[1], limits[1])
+	if !ok {
+		return 0, false
+	}
+
+	if longFraction > shortFraction {
+		return longFraction, true
+	}
+	return shortFraction, true
+}
+
+func parseCommaPair(header string) ([2]float64, bool) {
+	var values [2]float64
+	parts := strings.Split(header, ",")
+	if len(parts) != 2 {
+		return values, false
+	}
+	for i, part := range parts {
+		value, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return values, false
+		}
+		values[i] = value
+	}
+	return values, true
+}
+
+func safeFraction(usage, limit float64) (float64, bool) {
+	if limit <= 0 {
+		return 0, false
+	}
+	return usage / limit, true
+}
+
+// parseResetAt approximates the next rate-limit window reset from the
+// backend's Date header.
+func parseResetAt(dateHeader string) time.Time {
+	parsed, err := http.ParseTime(dateHeader)
+	if err != nil {
+		return time.Time{}
+	}
+	return parsed.Add(rateLimitResetWindow)
+}
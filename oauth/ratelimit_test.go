@@ -0,0 +1,121 @@
+package oauth
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func resetRateLimiter() {
+	rateLimiter.mu.Lock()
+	rateLimiter.fraction = 0
+	rateLimiter.resetAt = time.Time{}
+	rateLimiter.threshold = defaultRateLimitThreshold
+	rateLimiter.mu.Unlock()
+}
+
+func TestParseRateLimitFractionValid(t *testing.T) {
+	fraction, ok := parseRateLimitFraction("100,10000", "50,2000")
+
+	assert.True(t, ok)
+	assert.EqualValues(t, 0.5, fraction)
+}
+
+func TestParseRateLimitFractionInvalid(t *testing.T) {
+	_, ok := parseRateLimitFraction("not-a-number,10000", "50,2000")
+	assert.False(t, ok)
+
+	_, ok = parseRateLimitFraction("100,10000", "garbage")
+	assert.False(t, ok)
+}
+
+func TestGetAccessTokenTracksRateLimitHeaders(t *testing.T) {
+	resetTokenCache()
+	resetRateLimiter()
+	defer httpmock.Reset()
+
+	accessTokenId := "rate-limit-tracked"
+	fixture := fmt.Sprintf(`{"access_token":"%s","user_id":1,"client_id":2,"expires":%d}`, accessTokenId, time.Now().Add(time.Hour).Unix())
+	fakeUrl := fmt.Sprintf("http://localhost:8080/oauth/access_token/%s", accessTokenId)
+	rawResponse := httpmock.NewStringResponse(200, fixture)
+	rawResponse.Header.Set(headerXRatelimitLimit, "100,10000")
+	rawResponse.Header.Set(headerXRatelimitUsage, "95,200")
+	httpmock.RegisterResponder("GET", fakeUrl, httpmock.ResponderFromResponse(rawResponse))
+	httpmock.ActivateNonDefault(oauthRestClient.GetClient())
+
+	_, err := getAccessToken(accessTokenId)
+	assert.Nil(t, err)
+
+	fraction, _ := RateLimitStatus()
+	assert.EqualValues(t, 0.95, fraction)
+}
+
+func TestGetAccessTokenShortCircuitsWhenThresholdExceeded(t *testing.T) {
+	resetTokenCache()
+	resetRateLimiter()
+	defer resetRateLimiter()
+	defer httpmock.Reset()
+
+	rateLimiter.mu.Lock()
+	rateLimiter.fraction = 0.95
+	rateLimiter.resetAt = time.Now().Add(time.Minute)
+	rateLimiter.mu.Unlock()
+
+	accessTokenId := "rate-limited"
+	fakeUrl := fmt.Sprintf("http://localhost:8080/oauth/access_token/%s", accessTokenId)
+	httpmock.RegisterResponder("GET", fakeUrl, httpmock.NewStringResponder(500, `{"message":"should not be called","status":500,"error":"internal_server_error","causes":null}`))
+	httpmock.ActivateNonDefault(oauthRestClient.GetClient())
+
+	_, err := getAccessToken(accessTokenId)
+
+	assert.NotNil(t, err)
+	authErr, ok := err.(*AuthenticationError)
+	assert.True(t, ok)
+	assert.EqualValues(t, http.StatusServiceUnavailable, authErr.Status())
+	assert.EqualValues(t, 0, httpmock.GetTotalCallCount())
+}
+
+func TestRateLimiterSelfHealsWithoutDateHeader(t *testing.T) {
+	resetTokenCache()
+	resetRateLimiter()
+	defer resetRateLimiter()
+	defer httpmock.Reset()
+
+	accessTokenId := "rate-limit-no-date-header"
+	fixture := fmt.Sprintf(`{"access_token":"%s","user_id":1,"client_id":2,"expires":%d}`, accessTokenId, time.Now().Add(time.Hour).Unix())
+	fakeUrl := fmt.Sprintf("http://localhost:8080/oauth/access_token/%s", accessTokenId)
+	rawResponse := httpmock.NewStringResponse(200, fixture)
+	rawResponse.Header.Set(headerXRatelimitLimit, "100,10000")
+	rawResponse.Header.Set(headerXRatelimitUsage, "95,200")
+	// Deliberately no Date header: track() can't derive a reset time from
+	// the response, so exceeded() must still self-heal once its fallback
+	// window elapses instead of blocking forever.
+	httpmock.RegisterResponder("GET", fakeUrl, httpmock.ResponderFromResponse(rawResponse))
+	httpmock.ActivateNonDefault(oauthRestClient.GetClient())
+
+	_, err := getAccessToken(accessTokenId)
+	assert.Nil(t, err)
+	assert.True(t, rateLimiter.exceeded())
+
+	rateLimiter.mu.Lock()
+	rateLimiter.resetAt = time.Now().Add(-time.Second)
+	rateLimiter.mu.Unlock()
+
+	assert.False(t, rateLimiter.exceeded())
+}
+
+func TestSetRateLimitThreshold(t *testing.T) {
+	defer resetRateLimiter()
+
+	SetRateLimitThreshold(0.5)
+
+	rateLimiter.mu.Lock()
+	threshold := rateLimiter.threshold
+	rateLimiter.mu.Unlock()
+
+	assert.EqualValues(t, 0.5, threshold)
+}
@@ -0,0 +1,53 @@
+package oauth
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/tv2169145/store_utils-go/rest_errors"
+)
+
+// GetCallerScopes returns the scopes AuthenticateRequest stamped onto the
+// request, or nil if the request has none (or was never authenticated).
+func GetCallerScopes(request *http.Request) []string {
+	if request == nil {
+		return nil
+	}
+	raw := request.Header.Get(headerXCallerScopes)
+	if raw == "" {
+		return nil
+	}
+	return strings.Split(raw, ",")
+}
+
+// RequireScopes checks that the caller identified on request carries every
+// scope in required, returning a forbidden error describing the first one
+// that is missing.
+func RequireScopes(request *http.Request, required ...string) rest_errors.RestErr {
+	granted := make(map[string]bool)
+	for _, scope := range GetCallerScopes(request) {
+		granted[scope] = true
+	}
+
+	for _, scope := range required {
+		if !granted[scope] {
+			return rest_errors.NewRestError("missing required scope: "+scope, http.StatusForbidden, "forbidden", nil)
+		}
+	}
+	return nil
+}
+
+// ScopeMiddleware rejects requests whose caller does not carry every scope
+// in required. It must run after AuthenticateRequest has populated the
+// X-Caller-Scopes header.
+func ScopeMiddleware(required ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := RequireScopes(r, required...); err != nil {
+				http.Error(w, err.Message(), err.Status())
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
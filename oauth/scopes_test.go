@@ -0,0 +1,78 @@
+package oauth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCallerScopesWithNilRequest(t *testing.T) {
+	assert.Nil(t, GetCallerScopes(nil))
+}
+
+func TestGetCallerScopesNone(t *testing.T) {
+	request := http.Request{Header: make(http.Header)}
+	assert.Nil(t, GetCallerScopes(&request))
+}
+
+func TestGetCallerScopesSuccess(t *testing.T) {
+	request := http.Request{Header: make(http.Header)}
+	request.Header.Set(headerXCallerScopes, "read,write")
+
+	scopes := GetCallerScopes(&request)
+
+	assert.EqualValues(t, []string{"read", "write"}, scopes)
+}
+
+func TestRequireScopesMissing(t *testing.T) {
+	request := http.Request{Header: make(http.Header)}
+	request.Header.Set(headerXCallerScopes, "read")
+
+	err := RequireScopes(&request, "read", "write")
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusForbidden, err.Status())
+}
+
+func TestRequireScopesSuccess(t *testing.T) {
+	request := http.Request{Header: make(http.Header)}
+	request.Header.Set(headerXCallerScopes, "read,write")
+
+	err := RequireScopes(&request, "read", "write")
+
+	assert.Nil(t, err)
+}
+
+func TestScopeMiddlewareRejectsMissingScope(t *testing.T) {
+	handlerCalled := false
+	handler := ScopeMiddleware("write")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handlerCalled = true
+	}))
+
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:8080", nil)
+	request.Header.Set(headerXCallerScopes, "read")
+	recorder := httptest.NewRecorder()
+
+	handler.ServeHTTP(recorder, request)
+
+	assert.False(t, handlerCalled)
+	assert.EqualValues(t, http.StatusForbidden, recorder.Code)
+}
+
+func TestScopeMiddlewareAllowsGrantedScope(t *testing.T) {
+	handlerCalled := false
+	handler := ScopeMiddleware("read")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handlerCalled = true
+	}))
+
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:8080", nil)
+	request.Header.Set(headerXCallerScopes, "read,write")
+	recorder := httptest.NewRecorder()
+
+	handler.ServeHTTP(recorder, request)
+
+	assert.True(t, handlerCalled)
+	assert.EqualValues(t, http.StatusOK, recorder.Code)
+}